@@ -0,0 +1,50 @@
+package cdp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// EncodeCBOR converts a JSON-encoded CDP message into a CBOR encoding of the same
+// structure, for a pipe opened with --remote-debugging-pipe=cbor. This round-trips
+// through encoding/json's generic interface{} representation and fxamacker/cbor's
+// generic marshaler; it has not been verified byte-for-byte against Chrome's own
+// inspector_protocol CBOR writer, so treat --remote-debugging-pipe=cbor support as
+// unconfirmed until it's been exercised against a real Chrome binary.
+func EncodeCBOR(jsonMsg []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(jsonMsg, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON CDP message: %w", err)
+	}
+
+	out, err := cbor.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CDP message as CBOR: %w", err)
+	}
+	return out, nil
+}
+
+// DecodeCBOR converts a CBOR-encoded CDP message read from a --remote-debugging-pipe=cbor
+// pipe back into the JSON the rest of rod works with.
+func DecodeCBOR(cborMsg []byte) ([]byte, error) {
+	// Decode maps as map[string]interface{}: the default for an interface{} target
+	// is map[interface{}]interface{}, which encoding/json cannot marshal.
+	dm, err := cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]interface{}(nil))}.DecMode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up CBOR decoder: %w", err)
+	}
+
+	var v interface{}
+	if err := dm.Unmarshal(cborMsg, &v); err != nil {
+		return nil, fmt.Errorf("failed to decode CBOR CDP message: %w", err)
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encode CDP message as JSON: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,46 @@
+package cdp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestCBORRoundTrip checks that EncodeCBOR followed by DecodeCBOR reproduces the
+// original message, structurally, for the JSON shapes a CDP message takes (command
+// requests, events, and error/result replies). It does not verify the bytes match
+// what a --remote-debugging-pipe=cbor Chrome would emit or accept, only that rod's
+// own translation is internally consistent.
+func TestCBORRoundTrip(t *testing.T) {
+	cases := []string{
+		`{"id":1,"method":"Target.createTarget","params":{"url":"about:blank"}}`,
+		`{"id":1,"result":{"targetId":"abc123"}}`,
+		`{"method":"Target.targetCreated","params":{"targetInfo":{"targetId":"abc123","type":"page"}}}`,
+		`{"id":2,"error":{"code":-32000,"message":"No such target"}}`,
+		`{"id":3,"sessionId":"session-1","method":"Page.navigate","params":{"url":"https://example.com","extra":null}}`,
+	}
+
+	for _, in := range cases {
+		encoded, err := EncodeCBOR([]byte(in))
+		if err != nil {
+			t.Fatalf("EncodeCBOR(%s) failed: %v", in, err)
+		}
+
+		decoded, err := DecodeCBOR(encoded)
+		if err != nil {
+			t.Fatalf("DecodeCBOR failed for %s: %v", in, err)
+		}
+
+		var want, got interface{}
+		if err := json.Unmarshal([]byte(in), &want); err != nil {
+			t.Fatalf("failed to unmarshal input %s: %v", in, err)
+		}
+		if err := json.Unmarshal(decoded, &got); err != nil {
+			t.Fatalf("failed to unmarshal round-tripped output %s: %v", decoded, err)
+		}
+
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("round trip changed %s: got %s", in, decoded)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package cdp
+
+import (
+	"bufio"
+	"net"
+)
+
+// DialNamedPipe connects to a CDP pipe transport previously created with
+// ListenNamedPipe: a Windows named pipe (\\.\pipe\...) on Windows, or a Unix domain
+// socket everywhere else. It mirrors the wireguard-go/go-winio winpipe.Dial pattern,
+// giving a worker process a way to reach a browser's CDP pipe without the supervisor
+// that owns it exposing a TCP port.
+func DialNamedPipe(path string) (WebSocketable, error) {
+	conn, err := dialNamedPipe(path)
+	if err != nil {
+		return nil, err
+	}
+	return newSocketWebSocket(conn), nil
+}
+
+// ListenNamedPipe creates the server side of a CDP pipe transport at path: a Windows
+// named pipe whose security descriptor restricts access to the current user's SID,
+// or a Unix domain socket created with 0600 permissions. Each accepted connection
+// gets its own WebSocketable, so a supervisor process can launch a browser once and
+// hand out CDP access to multiple worker processes by pipe name.
+func ListenNamedPipe(path string) (*PipeListener, error) {
+	l, err := listenNamedPipe(path)
+	if err != nil {
+		return nil, err
+	}
+	return &PipeListener{l: l}, nil
+}
+
+// PipeListener is the server side of a CDP named-pipe/socket transport created by
+// ListenNamedPipe.
+type PipeListener struct {
+	l net.Listener
+}
+
+// Accept blocks until a client calls DialNamedPipe against the same path, and
+// returns a WebSocketable for that connection.
+func (pl *PipeListener) Accept() (WebSocketable, error) {
+	conn, err := pl.l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return newSocketWebSocket(conn), nil
+}
+
+// Close stops accepting new connections.
+func (pl *PipeListener) Close() error {
+	return pl.l.Close()
+}
+
+// socketWebSocket implements WebSocketable over a net.Conn (a Unix domain socket or
+// a Windows named pipe), using the same null-byte delimited framing as Chrome's own
+// anonymous-pipe protocol so both transports share one wire format.
+type socketWebSocket struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newSocketWebSocket(conn net.Conn) *socketWebSocket {
+	return &socketWebSocket{conn: conn, reader: bufio.NewReader(conn)}
+}
+
+// Send sends a CDP message to the peer on the other end of the socket/pipe.
+func (s *socketWebSocket) Send(data []byte) error {
+	_, err := s.conn.Write(append(data, '\x00'))
+	if err != nil {
+		_ = s.Close()
+	}
+	return err
+}
+
+// Read reads a CDP message from the peer on the other end of the socket/pipe.
+func (s *socketWebSocket) Read() ([]byte, error) {
+	data, err := s.reader.ReadBytes('\x00')
+	if err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+	if len(data) > 0 {
+		data = data[:len(data)-1]
+	}
+	return data, nil
+}
+
+// Close closes the underlying connection.
+func (s *socketWebSocket) Close() error {
+	return s.conn.Close()
+}
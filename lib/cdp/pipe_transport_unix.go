@@ -0,0 +1,40 @@
+//go:build !windows
+
+package cdp
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// dialNamedPipe connects to the Unix domain socket at path.
+func dialNamedPipe(path string) (net.Conn, error) {
+	return net.Dial("unix", path)
+}
+
+// listenNamedPipe creates a Unix domain socket at path, restricted to the owner
+// (0600) so other local users can't attach to the CDP endpoint it exposes.
+func listenNamedPipe(path string) (net.Listener, error) {
+	// A stale socket file from a previous, uncleanly-stopped supervisor would
+	// otherwise make net.Listen fail with "address already in use".
+	_ = os.Remove(path)
+
+	// net.Listen creates the socket with umask-dependent permissions, and the
+	// Chmod below only runs afterward; without narrowing the umask first, there is
+	// a window where the socket is briefly group/world-accessible and another
+	// local user's process could connect before Chmod takes effect.
+	oldUmask := syscall.Umask(0o177)
+	l, err := net.Listen("unix", path)
+	syscall.Umask(oldUmask)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+
+	return l, nil
+}
@@ -0,0 +1,51 @@
+//go:build windows
+
+package cdp
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/Microsoft/go-winio"
+	"golang.org/x/sys/windows"
+)
+
+// currentUserSDDL builds a security descriptor string that grants pipe access only
+// to the calling process's user and the system account, mirroring the Unix side's
+// 0600 socket permissions. It resolves the caller's actual SID rather than using the
+// OW (Owner Rights) well-known SID, which refers to whoever currently owns the
+// object's access rights, not the user who created it, and so doesn't restrict
+// access to just that user the way a literal SID does.
+func currentUserSDDL() (string, error) {
+	token := windows.GetCurrentProcessToken()
+	user, err := token.GetTokenUser()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve current user SID: %w", err)
+	}
+
+	sid, err := user.User.Sid.String()
+	if err != nil {
+		return "", fmt.Errorf("failed to stringify current user SID: %w", err)
+	}
+
+	return fmt.Sprintf("D:P(A;;GA;;;%s)(A;;GA;;;SY)", sid), nil
+}
+
+// dialNamedPipe connects to the Windows named pipe at path (\\.\pipe\...).
+func dialNamedPipe(path string) (net.Conn, error) {
+	return winio.DialPipe(path, nil)
+}
+
+// listenNamedPipe creates a Windows named pipe at path whose security descriptor
+// restricts access to the current user's SID, so another local user's process
+// can't attach to the CDP endpoint it exposes.
+func listenNamedPipe(path string) (net.Listener, error) {
+	sddl, err := currentUserSDDL()
+	if err != nil {
+		return nil, err
+	}
+
+	return winio.ListenPipe(path, &winio.PipeConfig{
+		SecurityDescriptor: sddl,
+	})
+}
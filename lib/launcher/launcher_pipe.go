@@ -1,7 +1,6 @@
 package launcher
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"os/exec"
@@ -15,6 +14,16 @@ import (
 // NewPipeMode returns a Launcher configured for pipe-based CDP communication.
 // This mode provides automatic zombie process prevention since Chrome dies when the pipe closes.
 // It removes RemoteDebuggingPort and Leakless flags, and sets RemoteDebuggingPipe.
+//
+// Pipe mode is also the more secure transport: unlike RemoteDebuggingPort, the pipe is
+// only reachable by the process that created it, so no other local process can attach
+// to the DevTools Protocol and hijack the browser session. Only use UseTCP if something
+// outside the current process (e.g. chrome://inspect or an external debugger) needs to
+// connect.
+//
+// NewPipeMode and UseTCP only set flags. The pipe transport is used by LaunchPipe and
+// MustLaunchPipe; Launch and MustLaunch do not branch on these flags and do not
+// launch over the pipe.
 func NewPipeMode() *Launcher {
 	l := New()
 	l.Delete(flags.RemoteDebuggingPort)
@@ -27,6 +36,31 @@ func NewPipeMode() *Launcher {
 	return l
 }
 
+// UseTCP switches l back to the legacy TCP remote-debugging transport
+// (--remote-debugging-port) instead of the pipe transport that NewPipeMode/LaunchPipe
+// use. The TCP debugging port accepts unauthenticated DevTools Protocol connections
+// from any process that can reach it, which has been used to hijack browser sessions
+// and exfiltrate data, so only opt into it when an external debugger genuinely needs
+// to attach.
+//
+// UseTCP prints a warning to stderr if RemoteDebuggingAddress is explicitly set to
+// something other than loopback, since that widens the exposure beyond the local
+// machine. Chrome itself defaults RemoteDebuggingAddress to 127.0.0.1 when it's not
+// set, so an unset/empty address is not warned about.
+func (l *Launcher) UseTCP() *Launcher {
+	l.Delete(flags.RemoteDebuggingPipe)
+	l.Set(flags.RemoteDebuggingPort)
+	l.Set(flags.Leakless)
+
+	if addr, has := l.Get(flags.RemoteDebuggingAddress); has && addr != "" &&
+		addr != "127.0.0.1" && addr != "localhost" && addr != "::1" {
+		fmt.Fprintln(os.Stderr, "[rod] warning: --remote-debugging-address is set to "+addr+
+			", which is not loopback; the DevTools endpoint may be reachable by other processes or hosts")
+	}
+
+	return l
+}
+
 // LaunchPipe launches browser with --remote-debugging-pipe and returns a CDP client.
 // Use NewPipeMode() to create a properly configured Launcher for this method.
 func (l *Launcher) LaunchPipe() (*cdp.Client, error) {
@@ -43,14 +77,16 @@ func (l *Launcher) LaunchPipe() (*cdp.Client, error) {
 
 	l.setupUserPreferences()
 
-	// Pipe for us to write, Chrome to read
-	chromeReadPipe, ourWritePipe, err := os.Pipe()
+	// Pipe for us to write, Chrome to read. newPipe is platform-specific: on Windows
+	// our end needs FILE_FLAG_OVERLAPPED, which os.Pipe's anonymous pipes never
+	// support, so PipeWebSocket's IOCP-based reads/writes can actually be cancelled.
+	chromeReadPipe, ourWritePipe, err := l.newPipe(false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create write pipe: %w", err)
 	}
 
 	// Pipe for Chrome to write, us to read
-	ourReadPipe, chromeWritePipe, err := os.Pipe()
+	ourReadPipe, chromeWritePipe, err := l.newPipe(true)
 	if err != nil {
 		chromeReadPipe.Close()
 		ourWritePipe.Close()
@@ -95,8 +131,15 @@ func (l *Launcher) LaunchPipe() (*cdp.Client, error) {
 		close(l.exit)
 	}()
 
-	// Create pipe-based WebSocket implementation
-	pipeWS := NewPipeWebSocket(ourReadPipe, ourWritePipe)
+	// Create pipe-based WebSocket implementation. Binary() switches this to the
+	// length-prefixed CBOR framing instead of the default null-delimited JSON.
+	opts := l.pipeOptsOrDefault()
+	var pipeWS cdp.WebSocketable
+	if mode, _ := l.Get(flags.RemoteDebuggingPipe); mode == "cbor" {
+		pipeWS = NewFramedPipeWebSocket(ourReadPipe, ourWritePipe, opts)
+	} else {
+		pipeWS = NewPipeWebSocket(ourReadPipe, ourWritePipe, opts)
+	}
 
 	// Create and start CDP client
 	client := cdp.New().Logger(defaults.CDP).Start(pipeWS)
@@ -110,54 +153,3 @@ func (l *Launcher) MustLaunchPipe() *cdp.Client {
 	utils.E(err)
 	return client
 }
-
-// PipeWebSocket implements cdp.WebSocketable using pipes.
-// Messages are null-byte delimited per Chrome's pipe protocol.
-type PipeWebSocket struct {
-	in     *os.File
-	out    *os.File
-	reader *bufio.Reader
-}
-
-// NewPipeWebSocket creates a new PipeWebSocket from the given file descriptors.
-// in is for reading CDP messages from Chrome, out is for sending to Chrome.
-func NewPipeWebSocket(in, out *os.File) *PipeWebSocket {
-	return &PipeWebSocket{
-		in:     in,
-		out:    out,
-		reader: bufio.NewReader(in),
-	}
-}
-
-// Send sends a CDP message to the browser.
-func (p *PipeWebSocket) Send(data []byte) error {
-	_, err := p.out.Write(append(data, '\x00'))
-	if err != nil {
-		_ = p.Close()
-	}
-	return err
-}
-
-// Read reads a CDP message from the browser.
-func (p *PipeWebSocket) Read() ([]byte, error) {
-	data, err := p.reader.ReadBytes('\x00')
-	if err != nil {
-		_ = p.Close()
-		return nil, err
-	}
-	// Remove the trailing null byte
-	if len(data) > 0 {
-		data = data[:len(data)-1]
-	}
-	return data, nil
-}
-
-// Close closes both pipe file descriptors.
-func (p *PipeWebSocket) Close() error {
-	err1 := p.in.Close()
-	err2 := p.out.Close()
-	if err1 != nil {
-		return err1
-	}
-	return err2
-}
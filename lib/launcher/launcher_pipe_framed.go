@@ -0,0 +1,177 @@
+package launcher
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/launcher/flags"
+)
+
+// defaultMaxFrameSize bounds how large a single framed pipe message may be before
+// FramedPipeWebSocket.Read rejects it, so a misbehaving or malicious target can't
+// force unbounded memory growth with a bogus length prefix.
+const defaultMaxFrameSize = 256 * 1024 * 1024
+
+// pipeOpts holds rod-side pipe transport options that have no matching Chrome
+// command-line flag (flags only carry string values). It lives on the Launcher
+// struct itself (see the pipeOpts field in launcher.go) rather than a side map, so it
+// doesn't outlive the Launcher it belongs to.
+type pipeOpts struct {
+	maxFrameSize int
+	writeBuffer  int
+	maxFlushSize int
+}
+
+func (l *Launcher) pipeOptsOrDefault() pipeOpts {
+	return l.pipeOpts
+}
+
+func (l *Launcher) mutatePipeOpts(fn func(*pipeOpts)) *Launcher {
+	fn(&l.pipeOpts)
+	return l
+}
+
+// Binary switches l to Chrome's binary pipe protocol (--remote-debugging-pipe=cbor):
+// each message is a 4-byte length prefix followed by that many bytes of CBOR, instead
+// of JSON delimited by a null byte. Prefer this for workloads that move large payloads
+// over the pipe (full-page screenshots, big Network.getResponseBody results), since it
+// never has to scan payload bytes looking for a delimiter and can't be corrupted by a
+// stray null byte in the data.
+//
+// Experimental: rod's CBOR encoding round-trips through a generic JSON<->CBOR
+// translation (see cdp.EncodeCBOR) that has not been verified against Chrome's actual
+// inspector_protocol wire format. Don't reach for this until that's been confirmed
+// against a real Chrome binary; the default null-delimited JSON pipe mode is the only
+// one with any such verification.
+func (l *Launcher) Binary() *Launcher {
+	l.Set(flags.RemoteDebuggingPipe, "cbor")
+	return l
+}
+
+// MaxPipeFrameSize sets the largest Binary-mode pipe frame Read will accept before
+// rejecting it and closing the pipe. 0 (the default) uses defaultMaxFrameSize.
+func (l *Launcher) MaxPipeFrameSize(n int) *Launcher {
+	return l.mutatePipeOpts(func(o *pipeOpts) { o.maxFrameSize = n })
+}
+
+// WithWriteBuffer sets how many pending frames PipeWebSocket.Send can queue for its
+// writer goroutine before blocking the caller. 0 (the default) uses
+// defaultWriteBufferSize.
+func (l *Launcher) WithWriteBuffer(n int) *Launcher {
+	return l.mutatePipeOpts(func(o *pipeOpts) { o.writeBuffer = n })
+}
+
+// MaxPipeFlushSize sets how many bytes PipeWebSocket's writer goroutine coalesces
+// into a single underlying write before flushing early. 0 (the default) uses
+// defaultMaxFlushSize.
+func (l *Launcher) MaxPipeFlushSize(n int) *Launcher {
+	return l.mutatePipeOpts(func(o *pipeOpts) { o.maxFlushSize = n })
+}
+
+// FramedPipeWebSocket implements cdp.WebSocketable over Chrome's binary
+// (--remote-debugging-pipe=cbor) pipe protocol. Each message is a 4-byte
+// little-endian length prefix followed by that many bytes of CBOR, translated
+// to/from the JSON the rest of rod works with.
+type FramedPipeWebSocket struct {
+	in  *os.File
+	out *os.File
+
+	reader       *bufio.Reader
+	maxFrameSize int
+	writer       *batchWriter
+}
+
+// NewFramedPipeWebSocket creates a FramedPipeWebSocket from the given pipe file
+// descriptors. opts configures the writer goroutine that batches Sends (the zero
+// value uses its defaults) and bounds the largest frame Read will accept via
+// maxFrameSize, which falls back to defaultMaxFrameSize when <= 0.
+func NewFramedPipeWebSocket(in, out *os.File, opts pipeOpts) *FramedPipeWebSocket {
+	maxFrameSize := opts.maxFrameSize
+	if maxFrameSize <= 0 {
+		maxFrameSize = defaultMaxFrameSize
+	}
+	p := &FramedPipeWebSocket{
+		in:           in,
+		out:          out,
+		reader:       bufio.NewReader(in),
+		maxFrameSize: maxFrameSize,
+	}
+	p.writer = newBatchWriter(opts.writeBuffer, opts.maxFlushSize, func(b []byte) error {
+		_, err := p.out.Write(b)
+		return err
+	})
+	return p
+}
+
+// Send sends a CDP message to the browser, translating it to a length-prefixed CBOR
+// frame and routing it through p's writer goroutine so concurrent Send calls can
+// never interleave frames on the underlying pipe.
+func (p *FramedPipeWebSocket) Send(data []byte) error {
+	payload, err := cdp.EncodeCBOR(data)
+	if err != nil {
+		return err
+	}
+
+	frame := make([]byte, 4+len(payload))
+	binary.LittleEndian.PutUint32(frame, uint32(len(payload)))
+	copy(frame[4:], payload)
+
+	if err := p.writer.send(frame); err != nil {
+		_ = p.Close()
+		return err
+	}
+	return nil
+}
+
+// Flush blocks until every message enqueued by Send before this call has been
+// written to the pipe.
+func (p *FramedPipeWebSocket) Flush() error {
+	return p.writer.Flush()
+}
+
+// Read reads one length-prefixed CBOR frame from the browser and translates it back to
+// JSON. It handles a length prefix or payload arriving split across multiple reads, and
+// rejects any frame whose declared length exceeds maxFrameSize before allocating a
+// buffer for it.
+func (p *FramedPipeWebSocket) Read() ([]byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(p.reader, header); err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+
+	size := binary.LittleEndian.Uint32(header)
+	if int(size) > p.maxFrameSize {
+		_ = p.Close()
+		return nil, fmt.Errorf("pipe frame of %d bytes exceeds max frame size of %d bytes", size, p.maxFrameSize)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(p.reader, payload); err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+
+	msg, err := cdp.DecodeCBOR(payload)
+	if err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Close stops the writer goroutine and closes both pipe file descriptors.
+func (p *FramedPipeWebSocket) Close() error {
+	p.writer.close()
+
+	err1 := p.in.Close()
+	err2 := p.out.Close()
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
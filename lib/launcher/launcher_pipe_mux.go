@@ -0,0 +1,279 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-rod/rod/lib/cdp"
+)
+
+// PipeMux lets multiple independent *cdp.Client instances share one Chrome pipe
+// pair. Chrome only exposes one pipe pair per process, but rod users commonly want
+// isolated cdp.Client instances (one per test, one per Browser wrapper); PipeMux
+// demultiplexes the single underlying connection by sessionId, and by id for
+// messages with no session, so none of them can see or cancel another's calls.
+// A message for a session no client has claimed yet, or with no session at all, is
+// broadcast to every client rather than dropped.
+type PipeMux struct {
+	ws cdp.WebSocketable
+
+	nextID uint64 // atomic, the next id in the mux's private id-space
+
+	mu       sync.Mutex
+	clients  map[*muxClient]struct{}
+	inflight map[uint64]muxInflight // muxed id -> the client awaiting that reply
+	sessions map[string]*muxClient  // sessionId -> the client that owns it
+	closed   bool
+}
+
+type muxInflight struct {
+	client     *muxClient
+	originalID uint64
+}
+
+// NewPipeMux starts demultiplexing ws, which is normally the transport a
+// Launcher's LaunchPipe or cdp.DialNamedPipe returned. Don't pass ws directly to a
+// cdp.Client once it's muxed; use PipeMux.Client() for each isolated CDP client instead.
+func NewPipeMux(ws cdp.WebSocketable) *PipeMux {
+	m := &PipeMux{
+		ws:       ws,
+		clients:  map[*muxClient]struct{}{},
+		inflight: map[uint64]muxInflight{},
+		sessions: map[string]*muxClient{},
+	}
+	go m.readLoop()
+	return m
+}
+
+// Client returns a virtual cdp.WebSocketable backed by m. Each call to Send rewrites
+// the message's id into the mux's private id-space so the reply routes back to the
+// right Client. Once a Client has sent a command carrying a sessionId it receives
+// that session's events, until another Client sends a command on the same session
+// and takes it over; command replies always go to the Client that sent the command.
+func (m *PipeMux) Client() cdp.WebSocketable {
+	c := &muxClient{mux: m}
+	c.cond = sync.NewCond(&c.mu)
+
+	m.mu.Lock()
+	m.clients[c] = struct{}{}
+	m.mu.Unlock()
+
+	return c
+}
+
+// cdpMessage is the subset of the CDP wire format PipeMux needs to read in order to
+// route a message. Replies keep their unknown fields because rewriteID patches the
+// raw JSON rather than re-marshaling this struct; muxClient.Send re-encodes only
+// id, method, params and sessionId, so any other field of an outgoing command is
+// dropped.
+type cdpMessage struct {
+	ID        uint64          `json:"id"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+}
+
+// readLoop is the single goroutine allowed to call m.ws.Read, so no two virtual
+// clients can race on the shared pipe's read side. It dispatches every message while
+// holding m.mu, which is the same lock Close uses to remove a client from m.clients/
+// m.sessions/m.inflight, so a client can never be delivered to and torn down at once.
+func (m *PipeMux) readLoop() {
+	for {
+		data, err := m.ws.Read()
+		if err != nil {
+			m.shutdown()
+			return
+		}
+
+		var msg cdpMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue // drop a malformed frame rather than tearing down every client
+		}
+
+		m.mu.Lock()
+		entry, isReply := m.inflight[msg.ID]
+		if isReply {
+			delete(m.inflight, msg.ID)
+		}
+
+		switch {
+		case isReply:
+			data = rewriteID(data, entry.originalID)
+			entry.client.deliver(data)
+
+		case msg.SessionID != "":
+			if target := m.sessions[msg.SessionID]; target != nil {
+				target.deliver(data)
+			} else {
+				// No client has claimed this session yet. Ownership is only recorded
+				// when a client sends a command carrying this sessionId, but Chrome
+				// emits session events (e.g. Target.attachedToTarget) as soon as it
+				// attaches, before the client that triggered the attach has sent
+				// anything on the new session -- broadcast instead of dropping so
+				// whichever client is about to claim it doesn't miss it.
+				for c := range m.clients {
+					c.deliver(data)
+				}
+			}
+
+		default:
+			// No id claims this as a reply and no session claims it: a browser-level
+			// event (e.g. Target.targetCreated) that every client needs to see.
+			for c := range m.clients {
+				c.deliver(data)
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+// rewriteID returns data with its top-level "id" field replaced by id, leaving every
+// other field (result, error, sessionId, ...) untouched.
+func rewriteID(data []byte, id uint64) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data
+	}
+
+	idJSON, err := json.Marshal(id)
+	if err != nil {
+		return data
+	}
+	fields["id"] = idJSON
+
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+func (m *PipeMux) shutdown() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+	m.closed = true
+
+	for c := range m.clients {
+		c.mu.Lock()
+		c.closed = true
+		c.mu.Unlock()
+		c.cond.Broadcast()
+	}
+	m.clients = map[*muxClient]struct{}{}
+	m.inflight = map[uint64]muxInflight{}
+	m.sessions = map[string]*muxClient{}
+}
+
+// muxClient is the cdp.WebSocketable PipeMux.Client hands to one isolated
+// *cdp.Client. It never touches the shared pipe directly: Send goes through the
+// owning PipeMux, and Read drains a private queue the mux's readLoop delivers to.
+// The queue is unbounded and deliver never drops from it, so a slow client can't
+// lose a command reply (which would otherwise hang the caller waiting on it forever)
+// or a browser event; it just lets the queue grow until the client catches up.
+type muxClient struct {
+	mux *PipeMux
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  [][]byte
+	closed bool
+}
+
+// Send rewrites data's id into the mux's private id-space, remembers the mapping so
+// the reply routes back to c, and forwards it on the shared pipe.
+func (c *muxClient) Send(data []byte) error {
+	var msg cdpMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("failed to decode outgoing CDP message: %w", err)
+	}
+
+	muxID := atomic.AddUint64(&c.mux.nextID, 1)
+
+	c.mux.mu.Lock()
+	if c.mux.closed {
+		c.mux.mu.Unlock()
+		return fmt.Errorf("pipe mux is closed")
+	}
+	c.mux.inflight[muxID] = muxInflight{client: c, originalID: msg.ID}
+	if msg.SessionID != "" {
+		c.mux.sessions[msg.SessionID] = c
+	}
+	c.mux.mu.Unlock()
+
+	out, err := json.Marshal(struct {
+		ID        uint64          `json:"id"`
+		Method    string          `json:"method,omitempty"`
+		Params    json.RawMessage `json:"params,omitempty"`
+		SessionID string          `json:"sessionId,omitempty"`
+	}{muxID, msg.Method, msg.Params, msg.SessionID})
+	if err != nil {
+		return fmt.Errorf("failed to re-encode outgoing CDP message: %w", err)
+	}
+
+	return c.mux.ws.Send(out)
+}
+
+// Read blocks until the mux's readLoop delivers a message addressed to c, or c is
+// closed.
+func (c *muxClient) Read() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.queue) == 0 && !c.closed {
+		c.cond.Wait()
+	}
+	if len(c.queue) == 0 {
+		return nil, fmt.Errorf("pipe mux client closed")
+	}
+
+	data := c.queue[0]
+	c.queue = c.queue[1:]
+	return data, nil
+}
+
+// deliver appends data to c's queue for Read to pick up, unless c is already closed.
+// It never drops a message that's been handed to it: a dropped command reply would
+// hang the cdp.Client call waiting on it forever, so the queue simply grows to fit
+// whatever a slow client hasn't drained yet.
+func (c *muxClient) deliver(data []byte) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return
+	}
+	c.queue = append(c.queue, data)
+	c.mu.Unlock()
+
+	c.cond.Signal()
+}
+
+// Close detaches c from the mux; it does not close the shared pipe, since other
+// clients may still be using it.
+func (c *muxClient) Close() error {
+	c.mux.mu.Lock()
+	delete(c.mux.clients, c)
+	for id, entry := range c.mux.inflight {
+		if entry.client == c {
+			delete(c.mux.inflight, id)
+		}
+	}
+	for sessionID, cl := range c.mux.sessions {
+		if cl == c {
+			delete(c.mux.sessions, sessionID)
+		}
+	}
+	c.mux.mu.Unlock()
+
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	c.cond.Broadcast()
+
+	return nil
+}
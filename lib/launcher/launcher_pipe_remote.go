@@ -0,0 +1,28 @@
+package launcher
+
+import (
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/defaults"
+	"github.com/go-rod/rod/lib/utils"
+)
+
+// NewRemotePipe connects to a CDP pipe transport previously exposed with
+// cdp.ListenNamedPipe, typically by a supervisor process that owns a browser
+// launched with LaunchPipe, and returns a ready CDP client. This lets worker
+// processes share one browser by pipe name instead of each needing
+// RemoteDebuggingPort's TCP exposure, or inheriting the raw anonymous-pipe file
+// descriptors a single LaunchPipe call hands out.
+func NewRemotePipe(path string) (*cdp.Client, error) {
+	ws, err := cdp.DialNamedPipe(path)
+	if err != nil {
+		return nil, err
+	}
+	return cdp.New().Logger(defaults.CDP).Start(ws), nil
+}
+
+// MustNewRemotePipe is similar to NewRemotePipe.
+func MustNewRemotePipe(path string) *cdp.Client {
+	client, err := NewRemotePipe(path)
+	utils.E(err)
+	return client
+}
@@ -3,10 +3,20 @@
 package launcher
 
 import (
+	"bufio"
 	"os"
 	"os/exec"
+	"time"
 )
 
+// newPipe creates one half-duplex pipe for the Chrome <-> rod pipe transport.
+// ourEndIsRead is unused on Unix: a plain os.Pipe's file descriptors are pollable in
+// either direction, so unlike Windows there's no separate overlapped-capable end to
+// create. Returns (readEnd, writeEnd), matching os.Pipe's own convention.
+func (l *Launcher) newPipe(ourEndIsRead bool) (r, w *os.File, err error) {
+	return os.Pipe()
+}
+
 // preparePipeConfig prepares Unix-specific pipe configuration.
 // Returns a closure to configure the command with ExtraFiles for FD 3 and 4.
 func (l *Launcher) preparePipeConfig(readPipe, writePipe *os.File) (func(cmd *exec.Cmd), error) {
@@ -15,3 +25,83 @@ func (l *Launcher) preparePipeConfig(readPipe, writePipe *os.File) (func(cmd *ex
 		cmd.ExtraFiles = []*os.File{readPipe, writePipe}
 	}, nil
 }
+
+// PipeWebSocket implements cdp.WebSocketable using pipes.
+// Messages are null-byte delimited per Chrome's pipe protocol.
+type PipeWebSocket struct {
+	in     *os.File
+	out    *os.File
+	reader *bufio.Reader
+	writer *batchWriter
+}
+
+// NewPipeWebSocket creates a new PipeWebSocket from the given file descriptors.
+// in is for reading CDP messages from Chrome, out is for sending to Chrome.
+// opts configures the writer goroutine that batches Sends; the zero value uses
+// its defaults.
+func NewPipeWebSocket(in, out *os.File, opts pipeOpts) *PipeWebSocket {
+	p := &PipeWebSocket{
+		in:     in,
+		out:    out,
+		reader: bufio.NewReader(in),
+	}
+	p.writer = newBatchWriter(opts.writeBuffer, opts.maxFlushSize, func(b []byte) error {
+		_, err := p.out.Write(b)
+		return err
+	})
+	return p
+}
+
+// Send writes a CDP message to the browser, blocking until it (possibly coalesced
+// with other pending messages into a single write syscall by p's writer goroutine)
+// has actually reached the pipe, and returns that write's error if it failed.
+func (p *PipeWebSocket) Send(data []byte) error {
+	err := p.writer.send(append(data, '\x00'))
+	if err != nil {
+		_ = p.Close()
+	}
+	return err
+}
+
+// Flush blocks until every message enqueued by Send before this call has been
+// written to the pipe.
+func (p *PipeWebSocket) Flush() error {
+	return p.writer.Flush()
+}
+
+// Read reads a CDP message from the browser.
+func (p *PipeWebSocket) Read() ([]byte, error) {
+	data, err := p.reader.ReadBytes('\x00')
+	if err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+	// Remove the trailing null byte
+	if len(data) > 0 {
+		data = data[:len(data)-1]
+	}
+	return data, nil
+}
+
+// SetReadDeadline sets the deadline for future Read calls, matching net.Conn semantics.
+// Unix pipe file descriptors are pollable, so this is a thin wrapper over os.File.
+func (p *PipeWebSocket) SetReadDeadline(t time.Time) error {
+	return p.in.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline for future Send calls, matching net.Conn semantics.
+func (p *PipeWebSocket) SetWriteDeadline(t time.Time) error {
+	return p.out.SetWriteDeadline(t)
+}
+
+// Close stops the writer goroutine and closes both pipe file descriptors.
+func (p *PipeWebSocket) Close() error {
+	p.writer.close()
+
+	err1 := p.in.Close()
+	err2 := p.out.Close()
+	if err1 != nil {
+		return err1
+	}
+	return err2
+}
@@ -6,9 +6,14 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
+	"unsafe"
 
 	"github.com/go-rod/rod/lib/launcher/flags"
+	"golang.org/x/sys/windows"
 )
 
 // preparePipeConfig prepares Windows-specific pipe configuration.
@@ -46,3 +51,315 @@ func (l *Launcher) preparePipeConfig(readPipe, writePipe *os.File) (func(cmd *ex
 func setHandleInheritable(handle syscall.Handle) error {
 	return syscall.SetHandleInformation(handle, syscall.HANDLE_FLAG_INHERIT, syscall.HANDLE_FLAG_INHERIT)
 }
+
+// pipeNameCounter makes each newPipe's pipe name unique within this process.
+var pipeNameCounter uint64
+
+// newPipe creates one half-duplex pipe for the Chrome <-> rod pipe transport. Unlike
+// Unix, a Windows anonymous pipe from CreatePipe (what os.Pipe wraps) never supports
+// FILE_FLAG_OVERLAPPED on either end, so PipeWebSocket's IOCP-based await could never
+// observe a completion and would block forever. Instead this creates a uniquely named
+// pipe with our end opened overlapped via CreateNamedPipe, and Chrome's end opened as
+// a plain synchronous, inheritable handle via CreateFile against that same name --
+// giving Chrome what looks like an ordinary anonymous pipe handle while letting our
+// side use overlapped I/O.
+func (l *Launcher) newPipe(ourEndIsRead bool) (r, w *os.File, err error) {
+	name := `\\.\pipe\rod-cdp-` + fmt.Sprint(os.Getpid()) + `-` + fmt.Sprint(atomic.AddUint64(&pipeNameCounter, 1))
+	namePtr, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode pipe name: %w", err)
+	}
+
+	ourAccess := uint32(windows.PIPE_ACCESS_OUTBOUND)
+	chromeAccess := uint32(windows.GENERIC_READ)
+	if ourEndIsRead {
+		ourAccess = windows.PIPE_ACCESS_INBOUND
+		chromeAccess = windows.GENERIC_WRITE
+	}
+
+	ourHandle, err := windows.CreateNamedPipe(
+		namePtr,
+		ourAccess|windows.FILE_FLAG_OVERLAPPED,
+		windows.PIPE_TYPE_BYTE|windows.PIPE_WAIT,
+		1,
+		readChunkSize,
+		readChunkSize,
+		0,
+		nil,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create overlapped pipe: %w", err)
+	}
+
+	sa := &windows.SecurityAttributes{
+		Length:        uint32(unsafe.Sizeof(windows.SecurityAttributes{})),
+		InheritHandle: 1,
+	}
+	chromeHandle, err := windows.CreateFile(
+		namePtr,
+		chromeAccess,
+		0,
+		sa,
+		windows.OPEN_EXISTING,
+		windows.FILE_ATTRIBUTE_NORMAL,
+		0,
+	)
+	if err != nil {
+		windows.CloseHandle(ourHandle)
+		return nil, nil, fmt.Errorf("failed to open pipe client end: %w", err)
+	}
+
+	ourFile := os.NewFile(uintptr(ourHandle), name)
+	chromeFile := os.NewFile(uintptr(chromeHandle), name)
+	if ourEndIsRead {
+		return ourFile, chromeFile, nil
+	}
+	return chromeFile, ourFile, nil
+}
+
+// readChunkSize is the size of the buffer used for each overlapped ReadFile call.
+const readChunkSize = 64 * 1024
+
+// PipeWebSocket implements cdp.WebSocketable over the pipes newPipe creates (named
+// pipes whose rod-side end is opened with FILE_FLAG_OVERLAPPED) using
+// overlapped (asynchronous) I/O dispatched through an IO completion port, following
+// the pattern wireguard-go/go-winio use for named pipes. A plain synchronous ReadFile
+// cannot be interrupted by Close from another goroutine and has no way to honor a
+// read deadline; routing through GetQueuedCompletionStatus gives us both, since
+// CancelIoEx can unblock an in-flight operation from any goroutine.
+type PipeWebSocket struct {
+	in  *os.File
+	out *os.File
+
+	iocp    windows.Handle
+	initErr error
+
+	mu            sync.Mutex
+	buf           []byte // bytes read but not yet consumed up to a '\x00' delimiter
+	readDeadline  time.Time
+	writeDeadline time.Time
+
+	writer *batchWriter
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewPipeWebSocket creates a new PipeWebSocket from the given file descriptors.
+// in is for reading CDP messages from Chrome, out is for sending to Chrome.
+// opts configures the writer goroutine that batches Sends; the zero value uses
+// its defaults.
+func NewPipeWebSocket(in, out *os.File, opts pipeOpts) *PipeWebSocket {
+	p := &PipeWebSocket{in: in, out: out, closed: make(chan struct{})}
+
+	iocp, err := windows.CreateIoCompletionPort(windows.Handle(in.Fd()), 0, 0, 1)
+	if err != nil {
+		p.initErr = fmt.Errorf("failed to associate read pipe with IO completion port: %w", err)
+		return p
+	}
+	if _, err := windows.CreateIoCompletionPort(windows.Handle(out.Fd()), iocp, 0, 1); err != nil {
+		p.initErr = fmt.Errorf("failed to associate write pipe with IO completion port: %w", err)
+		return p
+	}
+	p.iocp = iocp
+	p.writer = newBatchWriter(opts.writeBuffer, opts.maxFlushSize, p.writeOnce)
+
+	return p
+}
+
+// Send writes a CDP message to the browser, blocking until it (possibly coalesced
+// with other pending messages into a single overlapped WriteFile by p's writer
+// goroutine via writeOnce) has actually reached the pipe, and returns that write's
+// error if it failed.
+func (p *PipeWebSocket) Send(data []byte) error {
+	if p.initErr != nil {
+		return p.initErr
+	}
+
+	err := p.writer.send(append(data, '\x00'))
+	if err != nil {
+		_ = p.Close()
+	}
+	return err
+}
+
+// Flush blocks until every message enqueued by Send before this call has been
+// written to the pipe.
+func (p *PipeWebSocket) Flush() error {
+	return p.writer.Flush()
+}
+
+// writeOnce issues a single overlapped WriteFile for data and blocks until it
+// completes, is cancelled by Close, or the write deadline expires. It is the
+// underlying sink the writer goroutine calls once it has a batch ready to flush.
+func (p *PipeWebSocket) writeOnce(data []byte) error {
+	overlapped := &windows.Overlapped{}
+
+	err := windows.WriteFile(windows.Handle(p.out.Fd()), data, nil, overlapped)
+	if err != nil && err != windows.ERROR_IO_PENDING {
+		return fmt.Errorf("WriteFile failed: %w", err)
+	}
+
+	cancel := p.armDeadline(p.out, overlapped, p.getWriteDeadline())
+	defer cancel()
+
+	_, err = p.await(overlapped)
+	return err
+}
+
+// Read reads a CDP message from the browser.
+func (p *PipeWebSocket) Read() ([]byte, error) {
+	if p.initErr != nil {
+		return nil, p.initErr
+	}
+
+	for {
+		if i := indexNull(p.buf); i >= 0 {
+			msg := p.buf[:i]
+			p.buf = p.buf[i+1:]
+			return msg, nil
+		}
+
+		chunk, err := p.readChunk()
+		if err != nil {
+			_ = p.Close()
+			return nil, err
+		}
+		p.buf = append(p.buf, chunk...)
+	}
+}
+
+// readChunk issues a single overlapped ReadFile and blocks on the completion port
+// until it finishes, is cancelled by Close, or its read deadline expires.
+func (p *PipeWebSocket) readChunk() ([]byte, error) {
+	buf := make([]byte, readChunkSize)
+	overlapped := &windows.Overlapped{}
+
+	err := windows.ReadFile(windows.Handle(p.in.Fd()), buf, nil, overlapped)
+	if err != nil && err != windows.ERROR_IO_PENDING {
+		return nil, fmt.Errorf("ReadFile failed: %w", err)
+	}
+
+	cancel := p.armDeadline(p.in, overlapped, p.getReadDeadline())
+	defer cancel()
+
+	transferred, err := p.await(overlapped)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf[:transferred], nil
+}
+
+// await blocks until the completion matching overlapped is posted to the completion
+// port. Completions that belong to a different in-flight operation (e.g. a concurrent
+// Send while this goroutine is in Read) are re-posted so the goroutine waiting on
+// them still observes them.
+func (p *PipeWebSocket) await(overlapped *windows.Overlapped) (uint32, error) {
+	for {
+		var transferred uint32
+		var key uintptr
+		var done *windows.Overlapped
+
+		err := windows.GetQueuedCompletionStatus(p.iocp, &transferred, &key, &done, windows.INFINITE)
+		if done != overlapped {
+			if done != nil {
+				_ = windows.PostQueuedCompletionStatus(p.iocp, transferred, key, done)
+			}
+			continue
+		}
+
+		if err != nil {
+			select {
+			case <-p.closed:
+				return 0, fmt.Errorf("pipe closed")
+			default:
+			}
+			return 0, fmt.Errorf("overlapped I/O failed or was cancelled: %w", err)
+		}
+
+		return transferred, nil
+	}
+}
+
+// armDeadline starts a timer that cancels overlapped via CancelIoEx when deadline
+// elapses, matching net.Conn deadline semantics. It returns a func that must be
+// called once the operation finishes to stop the timer.
+func (p *PipeWebSocket) armDeadline(f *os.File, overlapped *windows.Overlapped, deadline time.Time) func() {
+	if deadline.IsZero() {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(time.Until(deadline), func() {
+		_ = windows.CancelIoEx(windows.Handle(f.Fd()), overlapped)
+	})
+	return func() { timer.Stop() }
+}
+
+// SetReadDeadline sets the deadline for future Read calls, matching net.Conn semantics.
+// Each read picks up the deadline in effect when it starts and is cancelled via
+// CancelIoEx once that deadline elapses; a read already in flight when
+// SetReadDeadline is called keeps the deadline it started with.
+func (p *PipeWebSocket) SetReadDeadline(t time.Time) error {
+	p.mu.Lock()
+	p.readDeadline = t
+	p.mu.Unlock()
+	return nil
+}
+
+// SetWriteDeadline sets the deadline for future Send calls, matching net.Conn semantics.
+func (p *PipeWebSocket) SetWriteDeadline(t time.Time) error {
+	p.mu.Lock()
+	p.writeDeadline = t
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *PipeWebSocket) getReadDeadline() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.readDeadline
+}
+
+func (p *PipeWebSocket) getWriteDeadline() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.writeDeadline
+}
+
+// Close closes both pipe file descriptors. Any reads or writes blocked in await are
+// cancelled via CancelIoEx so they unblock instead of waiting on GetQueuedCompletionStatus forever.
+func (p *PipeWebSocket) Close() error {
+	var closeErr error
+	p.closeOnce.Do(func() {
+		close(p.closed)
+
+		// Cancel both handles before waiting on the writer goroutine. If writeOnce is
+		// blocked in await on a stalled pipe with no write deadline set, it otherwise
+		// never observes stop, and writer.close() would hang forever.
+		_ = windows.CancelIoEx(windows.Handle(p.in.Fd()), nil)
+		_ = windows.CancelIoEx(windows.Handle(p.out.Fd()), nil)
+
+		if p.writer != nil {
+			p.writer.close()
+		}
+
+		err1 := p.in.Close()
+		err2 := p.out.Close()
+		if err1 != nil {
+			closeErr = err1
+			return
+		}
+		closeErr = err2
+	})
+	return closeErr
+}
+
+func indexNull(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,214 @@
+package launcher
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultWriteBufferSize is the default capacity of a batchWriter's pending-frame
+// channel, i.e. how many Send calls can be enqueued before one blocks waiting for
+// the writer goroutine to drain it.
+const defaultWriteBufferSize = 64
+
+// defaultMaxFlushSize is how many buffered bytes make batchWriter flush without
+// waiting for the timer. The frame that reaches it is included in that write, so a
+// single write can be larger.
+const defaultMaxFlushSize = 1 << 20 // 1MiB
+
+// defaultFlushInterval is how long batchWriter waits for more frames to arrive
+// before flushing whatever it has already buffered.
+const defaultFlushInterval = 500 * time.Microsecond
+
+// batchWriter serializes writes to a pipe through a single goroutine so that
+// concurrent Send calls from multiple goroutines can never interleave on the
+// underlying pipe, and coalesces adjacent small frames into fewer, larger writes.
+// PipeWebSocket.Send enqueues a frame and blocks until the batch it ends up in has
+// actually been written, so a write failure is reported to the caller that caused it
+// rather than merely stashed for a later call to discover.
+type batchWriter struct {
+	write func([]byte) error
+
+	frames chan queuedFrame
+	flush  chan chan error
+	stop   chan struct{}
+	done   chan struct{}
+
+	mu  sync.Mutex
+	err error
+
+	maxFlushSize int
+}
+
+// queuedFrame is one Send's payload together with the channel its error (or nil) is
+// delivered on once the batch containing it has been written.
+type queuedFrame struct {
+	data  []byte
+	reply chan error
+}
+
+// newBatchWriter starts the writer goroutine. bufferSize and maxFlushSize fall back
+// to their defaults when <= 0.
+func newBatchWriter(bufferSize, maxFlushSize int, write func([]byte) error) *batchWriter {
+	if bufferSize <= 0 {
+		bufferSize = defaultWriteBufferSize
+	}
+	if maxFlushSize <= 0 {
+		maxFlushSize = defaultMaxFlushSize
+	}
+
+	w := &batchWriter{
+		write:        write,
+		frames:       make(chan queuedFrame, bufferSize),
+		flush:        make(chan chan error),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+		maxFlushSize: maxFlushSize,
+	}
+	go w.loop()
+	return w
+}
+
+// send enqueues frame to be written by the writer goroutine and blocks until the
+// batch it ends up in has actually been written, returning that write's error (if
+// any) directly to the caller instead of only surfacing it on a later call.
+func (w *batchWriter) send(frame []byte) error {
+	reply := make(chan error, 1)
+	select {
+	case w.frames <- queuedFrame{data: frame, reply: reply}:
+	case <-w.done:
+		return w.lastErr()
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-w.done:
+		return w.lastErr()
+	}
+}
+
+// Flush blocks until every frame enqueued before this call has been written.
+func (w *batchWriter) Flush() error {
+	reply := make(chan error, 1)
+	select {
+	case w.flush <- reply:
+	case <-w.done:
+		return w.lastErr()
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-w.done:
+		return w.lastErr()
+	}
+}
+
+// close stops the writer goroutine after it flushes any frames already queued, and
+// waits for it to exit.
+func (w *batchWriter) close() {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	<-w.done
+}
+
+func (w *batchWriter) lastErr() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+func (w *batchWriter) setErr(err error) {
+	w.mu.Lock()
+	if w.err == nil {
+		w.err = err
+	}
+	w.mu.Unlock()
+}
+
+func (w *batchWriter) loop() {
+	defer close(w.done)
+
+	var pending []byte
+	var waiters []chan error
+
+	// timer only runs while pending is non-empty, so an idle connection isn't woken
+	// up ~2000 times a second for nothing; armed tracks whether it's currently set.
+	timer := time.NewTimer(defaultFlushInterval)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	armed := false
+	defer timer.Stop()
+
+	enqueue := func(f queuedFrame) {
+		pending = append(pending, f.data...)
+		waiters = append(waiters, f.reply)
+		if !armed {
+			timer.Reset(defaultFlushInterval)
+			armed = true
+		}
+	}
+
+	// drainReady absorbs every frame already sitting in w.frames without blocking, so
+	// Flush (and stop) account for everything a caller enqueued before they ran.
+	drainReady := func() {
+		for {
+			select {
+			case f := <-w.frames:
+				enqueue(f)
+			default:
+				return
+			}
+		}
+	}
+
+	flushPending := func() {
+		if len(pending) == 0 {
+			return
+		}
+		err := w.write(pending)
+		if err != nil {
+			w.setErr(err)
+		}
+		for _, reply := range waiters {
+			reply <- err
+		}
+		pending = pending[:0]
+		waiters = waiters[:0]
+
+		if armed {
+			if !timer.Stop() {
+				<-timer.C
+			}
+			armed = false
+		}
+	}
+
+	for {
+		select {
+		case frame := <-w.frames:
+			enqueue(frame)
+			if len(pending) >= w.maxFlushSize {
+				flushPending()
+			}
+
+		case reply := <-w.flush:
+			drainReady()
+			flushPending()
+			reply <- w.lastErr()
+
+		case <-timer.C:
+			armed = false
+			flushPending()
+
+		case <-w.stop:
+			drainReady()
+			flushPending()
+			return
+		}
+	}
+}